@@ -0,0 +1,140 @@
+// Command crdtokcl is a thin CLI wrapper around pkg/crdkcl: it parses
+// flags, wires a real filesystem/HTTP client/logger, and drives the
+// Converter (or cluster) pipeline.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/go-logr/stdr"
+
+	"kclImporter/pkg/crdkcl"
+)
+
+func main() {
+	rawURL := flag.String("url", "", "Source to list CRDs from: a GitHub or GitLab directory URL, a git remote, or a local directory")
+	moduleName := flag.String("name", "", "Module name")
+	configFile := flag.String("config", "", "Path to JSON config")
+	debugFlag := flag.Bool("debug", false, "Enable debugging")
+	cluster := flag.Bool("cluster", false, "List CRDs from a live Kubernetes cluster instead of -url/-config")
+	kubeconfig := flag.String("kubeconfig", "", "Path to a kubeconfig file (defaults to in-cluster config, then the usual kubeconfig lookup)")
+	kubeContext := flag.String("context", "", "Kubeconfig context to use")
+	labelSelector := flag.String("label-selector", "", "Only ingest CRDs matching this label selector")
+	nameSelector := flag.String("name-selector", "", "Only ingest CRDs whose name contains this substring")
+	allVersions := flag.Bool("all-versions", false, "Emit every version in spec.versions[] instead of just the storage version")
+	jobsFlag := flag.Int("jobs", runtime.NumCPU(), "Maximum number of concurrent downloads/conversions")
+	flag.Parse()
+
+	if *debugFlag {
+		stdr.SetVerbosity(1)
+	}
+	logger := stdr.New(log.New(os.Stdout, "", 0))
+
+	jobs := *jobsFlag
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	os.MkdirAll("modules", os.ModePerm)
+	os.MkdirAll("config", os.ModePerm)
+
+	ctx := context.Background()
+	converter := &crdkcl.Converter{
+		FS:   crdkcl.OSFS{},
+		HTTP: http.DefaultClient,
+		KCL:  crdkcl.NewRunner(),
+		Log:  logger,
+		Jobs: jobs,
+	}
+
+	var err error
+	switch {
+	case *cluster && *moduleName != "":
+		opts := crdkcl.ClusterOptions{
+			Kubeconfig:    *kubeconfig,
+			Context:       *kubeContext,
+			LabelSelector: *labelSelector,
+			NameSelector:  *nameSelector,
+			AllVersions:   *allVersions,
+		}
+		err = converter.RunCluster(ctx, opts, *moduleName)
+
+	case *rawURL != "" && *moduleName != "":
+		err = fetchFromSource(ctx, converter, *rawURL, *moduleName)
+
+	case *configFile != "":
+		err = runFromConfigFile(ctx, converter, *configFile)
+
+	default:
+		err = fmt.Errorf("configuration path is missing")
+	}
+
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("All tasks completed successfully.")
+}
+
+// fetchFromSource discovers the CRDs available at rawURL, saves them as
+// a JSON config for later re-use, and runs the pipeline against them. The
+// config is skipped for sources (like a git remote) whose CRDRefs are
+// ephemeral: they point into a temp clone that's gone by the time a
+// later -config run would try to re-download them.
+func fetchFromSource(ctx context.Context, converter *crdkcl.Converter, rawURL, moduleName string) error {
+	cfg, cleanup, ephemeral, err := crdkcl.DiscoverConfig(ctx, rawURL, moduleName)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	if ephemeral {
+		fmt.Printf("Skipping config file for %s: its CRD refs won't be reusable after this run\n", rawURL)
+	} else if err := saveConfigFile(moduleName, cfg); err != nil {
+		return err
+	}
+
+	return converter.Run(ctx, cfg)
+}
+
+// runFromConfigFile loads a previously saved JSON config and runs the
+// pipeline against it.
+func runFromConfigFile(ctx context.Context, converter *crdkcl.Converter, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("file not found: %s: %w", path, err)
+	}
+	defer file.Close()
+
+	var cfg crdkcl.Config
+	if err := json.NewDecoder(file).Decode(&cfg); err != nil {
+		return fmt.Errorf("decoding %s: %w", path, err)
+	}
+
+	return converter.Run(ctx, cfg)
+}
+
+// saveConfigFile writes cfg to config/<moduleName>.json so the same run
+// can be repeated later with -config instead of -url.
+func saveConfigFile(moduleName string, cfg crdkcl.Config) error {
+	data, err := json.MarshalIndent(cfg, "", "    ")
+	if err != nil {
+		return fmt.Errorf("encoding config: %w", err)
+	}
+
+	path := filepath.Join("config", moduleName+".json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+
+	fmt.Printf("JSON configuration saved to %s\n", path)
+	return nil
+}
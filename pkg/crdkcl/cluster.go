@@ -0,0 +1,162 @@
+package crdkcl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/yaml"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// ClusterOptions configures an in-cluster CRD ingestion run.
+type ClusterOptions struct {
+	Kubeconfig    string
+	Context       string
+	LabelSelector string
+	NameSelector  string
+	AllVersions   bool
+}
+
+// RunCluster lists CustomResourceDefinitions directly from a live cluster
+// via client-go, writes each one to modules/<moduleName>/crds/ as YAML,
+// and converts it straight into modules/<moduleName>/<group>/<version>/,
+// using spec.versions[].name as the authoritative API version instead of
+// guessing from the file name. Up to c.jobs() exports run concurrently,
+// all through c.FS the same as Download/Convert/Organize.
+func (c *Converter) RunCluster(ctx context.Context, opts ClusterOptions, moduleName string) error {
+	c.Module = moduleName
+
+	config, err := buildRestConfig(opts.Kubeconfig, opts.Context)
+	if err != nil {
+		return fmt.Errorf("building cluster config: %w", err)
+	}
+
+	clientset, err := apiextensionsclientset.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("building apiextensions client: %w", err)
+	}
+
+	list, err := clientset.ApiextensionsV1().CustomResourceDefinitions().List(ctx, metav1.ListOptions{
+		LabelSelector: opts.LabelSelector,
+	})
+	if err != nil {
+		return fmt.Errorf("listing CustomResourceDefinitions: %w", err)
+	}
+
+	moduleDir := ModuleDir(c.Module)
+	crdsDir := filepath.Join(moduleDir, "crds")
+	if err := c.FS.MkdirAll(crdsDir, os.ModePerm); err != nil {
+		return fmt.Errorf("creating %s: %w", crdsDir, err)
+	}
+
+	group, ctx := errgroup.WithContext(ctx)
+	group.SetLimit(c.jobs())
+
+	for _, crd := range list.Items {
+		if opts.NameSelector != "" && !strings.Contains(crd.Name, opts.NameSelector) {
+			continue
+		}
+
+		versions := crd.Spec.Versions
+		if !opts.AllVersions {
+			versions = []apiextensionsv1.CustomResourceDefinitionVersion{storageVersion(crd)}
+		}
+
+		for _, version := range versions {
+			crd, version := crd, version
+			group.Go(func() error {
+				return c.exportCRDVersion(ctx, crd, version, crdsDir, moduleDir)
+			})
+		}
+	}
+
+	if err := group.Wait(); err != nil {
+		return err
+	}
+
+	return c.removeEmptyDirs(moduleDir)
+}
+
+// storageVersion returns the version marked as the storage version, or
+// the first version if none is marked (which should not happen for a
+// well-formed CRD).
+func storageVersion(crd apiextensionsv1.CustomResourceDefinition) apiextensionsv1.CustomResourceDefinitionVersion {
+	for _, v := range crd.Spec.Versions {
+		if v.Storage {
+			return v
+		}
+	}
+	return crd.Spec.Versions[0]
+}
+
+// exportCRDVersion writes a copy of crd restricted to a single version to
+// crdsDir, then converts that copy into moduleDir/<group>/<version>/,
+// all through c.FS.
+func (c *Converter) exportCRDVersion(ctx context.Context, crd apiextensionsv1.CustomResourceDefinition, version apiextensionsv1.CustomResourceDefinitionVersion, crdsDir, moduleDir string) error {
+	single := crd
+	single.Spec.Versions = []apiextensionsv1.CustomResourceDefinitionVersion{version}
+
+	data, err := yaml.Marshal(single)
+	if err != nil {
+		return fmt.Errorf("marshaling CRD to YAML: %w", err)
+	}
+
+	crdFile := filepath.Join(crdsDir, crd.Name+"_"+version.Name+".yaml")
+	crdOut, err := c.FS.Create(crdFile)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", crdFile, err)
+	}
+	if _, err := crdOut.Write(data); err != nil {
+		crdOut.Close()
+		return fmt.Errorf("writing %s: %w", crdFile, err)
+	}
+	if err := crdOut.Close(); err != nil {
+		return fmt.Errorf("writing %s: %w", crdFile, err)
+	}
+
+	outputDir := filepath.Join(moduleDir, crd.Spec.Group, version.Name)
+	if err := c.FS.MkdirAll(outputDir, os.ModePerm); err != nil {
+		return fmt.Errorf("creating %s: %w", outputDir, err)
+	}
+	outputFile := filepath.Join(outputDir, crd.Name+".k")
+
+	schemaOut, err := c.FS.Create(outputFile)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", outputFile, err)
+	}
+
+	c.Log.Info("converting CRD", "input", crdFile, "output", outputFile)
+	if err := c.KCL.Convert(ctx, crdFile, bytes.NewReader(data), schemaOut); err != nil {
+		schemaOut.Close()
+		return fmt.Errorf("converting %s: %w", crdFile, err)
+	}
+	return schemaOut.Close()
+}
+
+// buildRestConfig resolves a *rest.Config the same way kubectl and most
+// client-go tools do: an in-cluster config when no kubeconfig path is
+// given (so the tool works as a workload inside the cluster it targets),
+// otherwise the kubeconfig on disk with an optional context override.
+func buildRestConfig(kubeconfig, context string) (*rest.Config, error) {
+	if kubeconfig == "" {
+		if config, err := rest.InClusterConfig(); err == nil {
+			return config, nil
+		}
+	}
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfig
+
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: context}
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+}
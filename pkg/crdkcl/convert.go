@@ -0,0 +1,107 @@
+package crdkcl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/yaml"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// apiVersionPattern matches an RFC-conformant Kubernetes API version:
+// v<N>, v<N>alpha<M>, or v<N>beta<M>.
+var apiVersionPattern = regexp.MustCompile(`^v\d+(alpha\d+|beta\d+)?$`)
+
+// Convert turns every CRD downloaded by Download into one KCL schema per
+// (group, version) it declares in spec.versions[], written to
+// modules/<Module>/<group>/<version>/, running up to Jobs conversions at
+// a time through KCL.
+func (c *Converter) Convert(ctx context.Context) error {
+	moduleDir := ModuleDir(c.Module)
+	crdsDir := filepath.Join(moduleDir, "crds")
+
+	group, ctx := errgroup.WithContext(ctx)
+	group.SetLimit(c.jobs())
+
+	for key := range c.crds {
+		key := key
+		group.Go(func() error {
+			inputFile := filepath.Join(crdsDir, key+".yaml")
+
+			data, err := fs.ReadFile(c.FS, inputFile)
+			if err != nil {
+				return fmt.Errorf("reading %s: %w", inputFile, err)
+			}
+
+			var crd apiextensionsv1.CustomResourceDefinition
+			if err := yaml.Unmarshal(data, &crd); err != nil {
+				return fmt.Errorf("parsing %s as a CRD: %w", inputFile, err)
+			}
+			if len(crd.Spec.Versions) == 0 {
+				return fmt.Errorf("%s declares no spec.versions", inputFile)
+			}
+
+			for _, version := range crd.Spec.Versions {
+				if !apiVersionPattern.MatchString(version.Name) {
+					c.Log.Info("CRD version does not look like a standard Kubernetes API version", "file", inputFile, "version", version.Name)
+				}
+				if err := c.convertVersion(ctx, crd, version, crdsDir, moduleDir, key); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+
+	return group.Wait()
+}
+
+// convertVersion writes a copy of crd restricted to version to crdsDir,
+// then converts that copy into moduleDir/<group>/<version>/<key>.k.
+func (c *Converter) convertVersion(ctx context.Context, crd apiextensionsv1.CustomResourceDefinition, version apiextensionsv1.CustomResourceDefinitionVersion, crdsDir, moduleDir, key string) error {
+	single := crd
+	single.Spec.Versions = []apiextensionsv1.CustomResourceDefinitionVersion{version}
+
+	data, err := yaml.Marshal(single)
+	if err != nil {
+		return fmt.Errorf("marshaling %s version %s: %w", key, version.Name, err)
+	}
+
+	versionFile := filepath.Join(crdsDir, key+"_"+version.Name+".yaml")
+	out, err := c.FS.Create(versionFile)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", versionFile, err)
+	}
+	if _, err := out.Write(data); err != nil {
+		out.Close()
+		return fmt.Errorf("writing %s: %w", versionFile, err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("writing %s: %w", versionFile, err)
+	}
+
+	outputDir := filepath.Join(moduleDir, crd.Spec.Group, version.Name)
+	if err := c.FS.MkdirAll(outputDir, os.ModePerm); err != nil {
+		return fmt.Errorf("creating %s: %w", outputDir, err)
+	}
+	outputFile := filepath.Join(outputDir, key+".k")
+
+	schemaOut, err := c.FS.Create(outputFile)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", outputFile, err)
+	}
+
+	c.Log.Info("converting CRD", "input", versionFile, "output", outputFile)
+	if err := c.KCL.Convert(ctx, versionFile, bytes.NewReader(data), schemaOut); err != nil {
+		schemaOut.Close()
+		return fmt.Errorf("conversion failed for %s: %w", versionFile, err)
+	}
+	return schemaOut.Close()
+}
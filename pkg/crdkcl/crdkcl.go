@@ -0,0 +1,101 @@
+// Package crdkcl downloads CustomResourceDefinitions from a forge, a git
+// remote, a local directory, or a live cluster, and converts them into
+// KCL schemas. It keeps its disk, network, and kcl-conversion
+// dependencies behind small interfaces (WritableFS, *http.Client, Runner)
+// so the pipeline can be embedded in a larger program or exercised in
+// tests without touching the real filesystem: Runner itself reads and
+// writes through io.Reader/io.Writer rather than file paths, so swapping
+// in an in-memory FS doesn't require a matching custom Runner.
+package crdkcl
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/go-logr/logr"
+)
+
+// CRDFiles maps a CRD file name to the URL (or file:// path) it can be
+// downloaded from.
+type CRDFiles map[string]string
+
+// Config is the input to Run: a module name and the CRDs that belong to it.
+type Config struct {
+	ModuleName string   `json:"moduleName"`
+	CRDs       CRDFiles `json:"crds"`
+}
+
+// Converter downloads, converts, and organizes the CRDs for a single
+// module. Its dependencies are all exported fields so callers can inject an
+// in-memory FS and a stub Runner in tests instead of the real disk,
+// network, and kcl binary.
+type Converter struct {
+	FS   WritableFS
+	HTTP *http.Client
+	KCL  Runner
+	Log  logr.Logger
+
+	// Module is the module name Download/Convert/Organize operate on.
+	Module string
+
+	// Jobs caps the number of concurrent downloads/conversions. Values
+	// below 1 are treated as 1.
+	Jobs int
+
+	crds CRDFiles // populated by Download, read back by Convert
+}
+
+// NewConverter returns a Converter wired to the real filesystem, the
+// default HTTP client, and NewRunner's fallback Runner.
+func NewConverter() *Converter {
+	return &Converter{
+		FS:   OSFS{},
+		HTTP: http.DefaultClient,
+		KCL:  NewRunner(),
+		Log:  logr.Discard(),
+		Jobs: 1,
+	}
+}
+
+func (c *Converter) jobs() int {
+	if c.Jobs < 1 {
+		return 1
+	}
+	return c.Jobs
+}
+
+// ModuleDir returns the directory a module's files live under, relative
+// to the Converter's FS.
+func ModuleDir(moduleName string) string {
+	return filepath.Join("modules", moduleName)
+}
+
+// Run downloads, converts, and organizes every CRD in cfg.CRDs under
+// modules/<cfg.ModuleName>.
+func (c *Converter) Run(ctx context.Context, cfg Config) error {
+	c.Module = cfg.ModuleName
+
+	moduleDir := ModuleDir(c.Module)
+	if err := c.FS.MkdirAll(filepath.Join(moduleDir, "crds"), os.ModePerm); err != nil {
+		return fmt.Errorf("creating %s: %w", moduleDir, err)
+	}
+
+	if err := c.Download(ctx, cfg.CRDs); err != nil {
+		return err
+	}
+	if err := c.Convert(ctx); err != nil {
+		return err
+	}
+	return c.Organize(ctx)
+}
+
+// Run builds a Converter wired to the real filesystem, network, and kcl
+// runner and runs the pipeline for cfg. It's the entry point for callers
+// that just want the default, on-disk behavior; anyone who needs to
+// inject a fake FS or Runner should construct a Converter directly.
+func Run(ctx context.Context, cfg Config) error {
+	return NewConverter().Run(ctx, cfg)
+}
@@ -0,0 +1,162 @@
+package crdkcl_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+
+	"kclImporter/pkg/crdkcl"
+)
+
+// stubRunner stands in for a real kcl Runner: instead of generating a
+// KCL schema it writes a fixed stand-in body to output, so Organize has
+// something to find.
+type stubRunner struct {
+	calls []string
+}
+
+func (r *stubRunner) Convert(ctx context.Context, name string, input io.Reader, output io.Writer) error {
+	r.calls = append(r.calls, name)
+	_, err := output.Write([]byte("schema Stub:\n    pass\n"))
+	return err
+}
+
+const widgetV1CRD = `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+spec:
+  group: example.com
+  versions:
+  - name: v1
+    schema:
+      openAPIV3Schema:
+        type: object
+`
+
+const gadgetV2Alpha1CRD = `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: gadgets.example.com
+spec:
+  group: example.com
+  versions:
+  - name: v2alpha1
+    schema:
+      openAPIV3Schema:
+        type: object
+`
+
+const oddballVersionCRD = `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: oddities.example.com
+spec:
+  group: example.com
+  versions:
+  - name: 1.0-rc1
+    schema:
+      openAPIV3Schema:
+        type: object
+`
+
+func TestConverterRun(t *testing.T) {
+	cases := []struct {
+		name       string
+		moduleName string
+		crdPath    string
+		crdContent string
+		crdKey     string
+		wantFile   string
+	}{
+		{
+			name:       "stable version is sorted under its group and version",
+			moduleName: "widgets",
+			crdPath:    "/widgets_v1.yaml",
+			crdContent: widgetV1CRD,
+			crdKey:     "widgets_v1",
+			wantFile:   "modules/widgets/example.com/v1/widgets_v1.k",
+		},
+		{
+			name:       "alpha version is sorted under its group and version",
+			moduleName: "gadgets",
+			crdPath:    "/gadgets_v2alpha1.yaml",
+			crdContent: gadgetV2Alpha1CRD,
+			crdKey:     "gadgets_v2alpha1",
+			wantFile:   "modules/gadgets/example.com/v2alpha1/gadgets_v2alpha1.k",
+		},
+		{
+			name:       "non-conforming version is still converted, just warned about",
+			moduleName: "oddities",
+			crdPath:    "/oddities.yaml",
+			crdContent: oddballVersionCRD,
+			crdKey:     "oddities",
+			wantFile:   "modules/oddities/example.com/1.0-rc1/oddities.k",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc(tc.crdPath, func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tc.crdContent))
+			})
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			fs := newMemFS()
+			runner := &stubRunner{}
+			converter := &crdkcl.Converter{
+				FS:   fs,
+				HTTP: server.Client(),
+				KCL:  runner,
+				Log:  logr.Discard(),
+				Jobs: 2,
+			}
+
+			cfg := crdkcl.Config{
+				ModuleName: tc.moduleName,
+				CRDs:       crdkcl.CRDFiles{tc.crdKey: server.URL + tc.crdPath},
+			}
+
+			if err := converter.Run(context.Background(), cfg); err != nil {
+				t.Fatalf("Run() = %v", err)
+			}
+
+			if _, err := fs.ReadFile(tc.wantFile); err != nil {
+				t.Errorf("expected output file %s, got error: %v", tc.wantFile, err)
+			}
+			if len(runner.calls) != 1 {
+				t.Errorf("expected KCL.Convert to be called once, got %d calls", len(runner.calls))
+			}
+		})
+	}
+}
+
+func TestConverterRunPropagatesDownloadErrors(t *testing.T) {
+	fs := newMemFS()
+	converter := &crdkcl.Converter{
+		FS:   fs,
+		HTTP: http.DefaultClient,
+		KCL:  &stubRunner{},
+		Log:  logr.Discard(),
+		Jobs: 1,
+	}
+
+	cfg := crdkcl.Config{
+		ModuleName: "broken",
+		CRDs:       crdkcl.CRDFiles{"broken": "http://127.0.0.1:0/does-not-exist.yaml"},
+	}
+
+	if err := converter.Run(context.Background(), cfg); err == nil {
+		t.Fatal("Run() = nil, want an error for an unreachable CRD source")
+	}
+}
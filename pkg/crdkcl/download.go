@@ -0,0 +1,80 @@
+package crdkcl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Download fetches every CRD in crds and writes it to
+// modules/<Module>/crds/<name>.yaml, running up to Jobs downloads at a
+// time.
+func (c *Converter) Download(ctx context.Context, crds CRDFiles) error {
+	c.crds = crds
+	crdsDir := filepath.Join(ModuleDir(c.Module), "crds")
+
+	group, ctx := errgroup.WithContext(ctx)
+	group.SetLimit(c.jobs())
+
+	for key, url := range crds {
+		key, url := key, url
+		group.Go(func() error {
+			filePath := filepath.Join(crdsDir, key+".yaml")
+			c.Log.Info("downloading CRD", "name", key, "url", url)
+			if err := c.downloadFile(ctx, filePath, url); err != nil {
+				return fmt.Errorf("download failed for %s: %w", url, err)
+			}
+			return nil
+		})
+	}
+
+	return group.Wait()
+}
+
+// downloadFile fetches a file from url and writes it to filePath via FS.
+// A "file://" URL is copied from local disk instead of being requested
+// over HTTP, which lets the Git and local-directory source drivers reuse
+// this step the same as the forge APIs.
+func (c *Converter) downloadFile(ctx context.Context, filePath, url string) error {
+	out, err := c.FS.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if localPath, ok := strings.CutPrefix(url, "file://"); ok {
+		in, err := os.Open(localPath)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+
+		_, err = io.Copy(out, in)
+		return err
+	}
+
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, err = io.Copy(out, resp.Body)
+	return err
+}
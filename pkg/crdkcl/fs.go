@@ -0,0 +1,38 @@
+package crdkcl
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// WritableFS is the filesystem surface the pipeline needs: enough of
+// fs.FS to read files back (including via the generic fs.ReadDir/
+// fs.ReadFile helpers), plus the handful of mutating operations a plain
+// fs.FS can't do. Tests can satisfy this with an in-memory implementation
+// instead of touching the real disk.
+type WritableFS interface {
+	fs.FS
+	MkdirAll(path string, perm fs.FileMode) error
+	Create(name string) (io.WriteCloser, error)
+	Rename(oldpath, newpath string) error
+	Remove(name string) error
+	Walk(root string, fn filepath.WalkFunc) error
+}
+
+// OSFS is a WritableFS backed by the real filesystem, rooted at the
+// process's current directory.
+type OSFS struct{}
+
+func (OSFS) Open(name string) (fs.File, error) { return os.Open(name) }
+
+func (OSFS) MkdirAll(path string, perm fs.FileMode) error { return os.MkdirAll(path, perm) }
+
+func (OSFS) Create(name string) (io.WriteCloser, error) { return os.Create(name) }
+
+func (OSFS) Rename(oldpath, newpath string) error { return os.Rename(oldpath, newpath) }
+
+func (OSFS) Remove(name string) error { return os.Remove(name) }
+
+func (OSFS) Walk(root string, fn filepath.WalkFunc) error { return filepath.Walk(root, fn) }
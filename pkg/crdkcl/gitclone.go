@@ -0,0 +1,51 @@
+package crdkcl
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// runGitCLI shallow-clones repoURL at ref into dir using the system git
+// binary. It is tried before the go-git fallback because the CLI is
+// faster and handles auth (credential helpers, SSH agents) for free.
+func runGitCLI(ctx context.Context, repoURL, ref, dir string) (string, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return "", fmt.Errorf("git binary not found on PATH: %w", err)
+	}
+
+	args := []string{"clone", "--depth", "1"}
+	if ref != "" && ref != "HEAD" {
+		args = append(args, "--branch", ref)
+	}
+	args = append(args, repoURL, dir)
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return string(out), fmt.Errorf("git clone %s: %w", repoURL, err)
+	}
+	return string(out), nil
+}
+
+// listLocalYAML walks dir and returns a CRDRef for every YAML file found,
+// with URL set to a file:// path so downloadFile copies it instead of
+// making an HTTP request.
+func listLocalYAML(dir string) ([]CRDRef, error) {
+	var refs []CRDRef
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && isYAMLFile(info.Name()) {
+			refs = append(refs, CRDRef{Name: info.Name(), URL: "file://" + path})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walking %s: %w", dir, err)
+	}
+	return refs, nil
+}
@@ -0,0 +1,197 @@
+package crdkcl_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// memFS is a minimal in-memory crdkcl.WritableFS used by tests so the
+// pipeline never touches the real disk.
+type memFS struct {
+	mu    sync.Mutex
+	files map[string][]byte
+	dirs  map[string]bool
+}
+
+func newMemFS() *memFS {
+	return &memFS{files: map[string][]byte{}, dirs: map[string]bool{".": true}}
+}
+
+func clean(name string) string {
+	return filepath.ToSlash(filepath.Clean(name))
+}
+
+// markDirs records every ancestor directory of a file path as existing,
+// the same way MkdirAll(filepath.Dir(name)) would.
+func (m *memFS) markDirs(name string) {
+	for dir := clean(filepath.Dir(name)); dir != "." && dir != "/" && dir != ""; dir = clean(filepath.Dir(dir)) {
+		m.dirs[dir] = true
+	}
+	m.dirs["."] = true
+}
+
+func (m *memFS) MkdirAll(path string, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for p := clean(path); p != "." && p != "/" && p != ""; p = clean(filepath.Dir(p)) {
+		m.dirs[p] = true
+	}
+	m.dirs["."] = true
+	return nil
+}
+
+type memWriteCloser struct {
+	fs   *memFS
+	name string
+	buf  bytes.Buffer
+}
+
+func (w *memWriteCloser) Write(p []byte) (int, error) { return w.buf.Write(p) }
+
+func (w *memWriteCloser) Close() error {
+	w.fs.mu.Lock()
+	defer w.fs.mu.Unlock()
+	w.fs.files[w.name] = append([]byte(nil), w.buf.Bytes()...)
+	w.fs.markDirs(w.name)
+	return nil
+}
+
+func (m *memFS) Create(name string) (io.WriteCloser, error) {
+	return &memWriteCloser{fs: m, name: clean(name)}, nil
+}
+
+func (m *memFS) Rename(oldpath, newpath string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	oldpath, newpath = clean(oldpath), clean(newpath)
+	data, ok := m.files[oldpath]
+	if !ok {
+		return fmt.Errorf("memfs: no such file: %s", oldpath)
+	}
+	delete(m.files, oldpath)
+	m.files[newpath] = data
+	m.markDirs(newpath)
+	return nil
+}
+
+func (m *memFS) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name = clean(name)
+	if _, ok := m.files[name]; ok {
+		delete(m.files, name)
+		return nil
+	}
+	if m.dirs[name] {
+		delete(m.dirs, name)
+		return nil
+	}
+	return fmt.Errorf("memfs: no such file or directory: %s", name)
+}
+
+func (m *memFS) Walk(root string, fn filepath.WalkFunc) error {
+	m.mu.Lock()
+	root = clean(root)
+	var paths []string
+	for dir := range m.dirs {
+		if dir == root || strings.HasPrefix(dir, root+"/") {
+			paths = append(paths, dir)
+		}
+	}
+	for file := range m.files {
+		if file == root || strings.HasPrefix(file, root+"/") {
+			paths = append(paths, file)
+		}
+	}
+	sort.Strings(paths)
+	m.mu.Unlock()
+
+	for _, p := range paths {
+		if err := fn(p, m.fileInfo(p), nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *memFS) fileInfo(name string) fs.FileInfo {
+	data, isFile := m.files[name]
+	return memFileInfo{name: filepath.Base(name), size: int64(len(data)), isDir: !isFile}
+}
+
+func (m *memFS) Open(name string) (fs.File, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name = clean(name)
+	data, ok := m.files[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", fs.ErrNotExist, name)
+	}
+	return &memFile{info: memFileInfo{name: filepath.Base(name), size: int64(len(data))}, r: bytes.NewReader(data)}, nil
+}
+
+// ReadDir lets fs.ReadDir(m, dir) work without going through Open.
+func (m *memFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name = clean(name)
+	var entries []fs.DirEntry
+	for dir := range m.dirs {
+		if clean(filepath.Dir(dir)) == name {
+			entries = append(entries, fs.FileInfoToDirEntry(memFileInfo{name: filepath.Base(dir), isDir: true}))
+		}
+	}
+	for file := range m.files {
+		if clean(filepath.Dir(file)) == name {
+			entries = append(entries, fs.FileInfoToDirEntry(memFileInfo{name: filepath.Base(file), size: int64(len(m.files[file]))}))
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
+
+// ReadFile lets fs.ReadFile(m, name) work without going through Open.
+func (m *memFS) ReadFile(name string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	data, ok := m.files[clean(name)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", fs.ErrNotExist, name)
+	}
+	return append([]byte(nil), data...), nil
+}
+
+type memFileInfo struct {
+	name  string
+	size  int64
+	isDir bool
+}
+
+func (i memFileInfo) Name() string       { return i.name }
+func (i memFileInfo) Size() int64        { return i.size }
+func (i memFileInfo) Mode() fs.FileMode  { return 0644 }
+func (i memFileInfo) ModTime() time.Time { return time.Time{} }
+func (i memFileInfo) IsDir() bool        { return i.isDir }
+func (i memFileInfo) Sys() interface{}   { return nil }
+
+type memFile struct {
+	info memFileInfo
+	r    *bytes.Reader
+}
+
+func (f *memFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *memFile) Read(p []byte) (int, error) { return f.r.Read(p) }
+func (f *memFile) Close() error               { return nil }
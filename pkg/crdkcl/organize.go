@@ -0,0 +1,137 @@
+package crdkcl
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Organize removes the redundant "regex_match = regex.match" helper kcl
+// emits once per generated file (Convert already writes each KCL schema
+// straight into its final modules/<Module>/<group>/<version>/ directory,
+// so there is nothing left to move) and prunes any directories left
+// empty behind it.
+func (c *Converter) Organize(ctx context.Context) error {
+	moduleDir := ModuleDir(c.Module)
+
+	dirs, err := c.kclFileDirs(moduleDir)
+	if err != nil {
+		return err
+	}
+	for _, dir := range dirs {
+		if err := c.removeRedundantRegexMatch(dir); err != nil {
+			return err
+		}
+	}
+
+	return c.removeEmptyDirs(moduleDir)
+}
+
+// kclFileDirs walks moduleDir and returns every directory that directly
+// contains at least one ".k" file, discovered from the tree itself
+// rather than from a fixed list of API versions.
+func (c *Converter) kclFileDirs(moduleDir string) ([]string, error) {
+	seen := map[string]bool{}
+	var dirs []string
+	err := c.FS.Walk(moduleDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(info.Name(), ".k") {
+			return nil
+		}
+		dir := filepath.Dir(path)
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walking %s: %w", moduleDir, err)
+	}
+	return dirs, nil
+}
+
+// removeRedundantRegexMatch removes redundant occurrences of the string
+// "regex_match = regex.match" from the ".k" files in dir: kcl's generator
+// emits the helper once per file, which is harmless but noisy when every
+// file in a directory repeats it.
+func (c *Converter) removeRedundantRegexMatch(dir string) error {
+	entries, err := fs.ReadDir(c.FS, dir)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", dir, err)
+	}
+
+	found := false
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".k") {
+			continue
+		}
+
+		filePath := filepath.Join(dir, entry.Name())
+		content, err := fs.ReadFile(c.FS, filePath)
+		if err != nil {
+			continue
+		}
+		if !strings.Contains(string(content), "regex_match = regex.match") {
+			continue
+		}
+		if !found {
+			found = true
+			continue
+		}
+
+		newContent := strings.Replace(string(content), "regex_match = regex.match", "", 1)
+		out, err := c.FS.Create(filePath)
+		if err != nil {
+			return fmt.Errorf("writing %s: %w", filePath, err)
+		}
+		_, writeErr := out.Write([]byte(newContent))
+		out.Close()
+		if writeErr != nil {
+			return fmt.Errorf("writing %s: %w", filePath, writeErr)
+		}
+		c.Log.V(1).Info("removed redundant regex_match helper", "file", filePath)
+	}
+	return nil
+}
+
+// removeEmptyDirs repeatedly removes empty directories under root until
+// none remain, since removing a leaf directory can leave its parent
+// empty in turn.
+func (c *Converter) removeEmptyDirs(root string) error {
+	for {
+		var empty []string
+		err := c.FS.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() && path != root && c.isEmptyDir(path) {
+				empty = append(empty, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("walking %s: %w", root, err)
+		}
+		if len(empty) == 0 {
+			return nil
+		}
+
+		for i := len(empty) - 1; i >= 0; i-- {
+			c.Log.V(1).Info("removing empty directory", "dir", empty[i])
+			if err := c.FS.Remove(empty[i]); err != nil {
+				return fmt.Errorf("removing %s: %w", empty[i], err)
+			}
+		}
+	}
+}
+
+func (c *Converter) isEmptyDir(path string) bool {
+	entries, err := fs.ReadDir(c.FS, path)
+	return err == nil && len(entries) == 0
+}
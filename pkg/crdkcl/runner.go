@@ -0,0 +1,148 @@
+package crdkcl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	"gopkg.in/yaml.v3"
+	"kcl-lang.io/kcl-go/pkg/tools/gen"
+)
+
+// Runner converts a single CRD YAML document, read from input and
+// identified by name (used for diagnostics and as the generated schema's
+// label), into a KCL schema written to output. Reading and writing
+// through the caller's streams rather than file paths means a Runner
+// works the same whether the pipeline is driven off real disk or an
+// in-memory WritableFS.
+type Runner interface {
+	Convert(ctx context.Context, name string, input io.Reader, output io.Writer) error
+}
+
+// crdSchemaDocument is the minimal shape of a CustomResourceDefinition
+// needed to pull out its validation schema.
+type crdSchemaDocument struct {
+	Spec struct {
+		Versions []struct {
+			Schema struct {
+				OpenAPIV3Schema map[string]interface{} `yaml:"openAPIV3Schema"`
+			} `yaml:"schema"`
+		} `yaml:"versions"`
+	} `yaml:"spec"`
+}
+
+// sdkRunner converts CRDs in-process via kcl-go's tools/gen package,
+// treating a version's openAPIV3Schema as a JSON Schema document. This
+// keeps the common case from shelling out to the kcl binary at all.
+type sdkRunner struct{}
+
+func (sdkRunner) Convert(ctx context.Context, name string, input io.Reader, output io.Writer) error {
+	data, err := io.ReadAll(input)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", name, err)
+	}
+
+	var doc crdSchemaDocument
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("parsing %s as a CRD: %w", name, err)
+	}
+	if len(doc.Spec.Versions) == 0 || doc.Spec.Versions[0].Schema.OpenAPIV3Schema == nil {
+		return fmt.Errorf("%s has no openAPIV3Schema to convert", name)
+	}
+
+	schemaJSON, err := json.Marshal(doc.Spec.Versions[0].Schema.OpenAPIV3Schema)
+	if err != nil {
+		return fmt.Errorf("re-encoding schema for %s as JSON: %w", name, err)
+	}
+
+	if err := gen.GenKcl(output, name, schemaJSON, &gen.GenKclOptions{Mode: gen.ModeJsonSchema}); err != nil {
+		return fmt.Errorf("generating KCL schema for %s: %w", name, err)
+	}
+	return nil
+}
+
+// execRunner shells out to the kcl CLI, matching the tool's original
+// behavior. It lives on as the fallback for documents the SDK can't
+// make sense of, and for environments where the SDK's generated code
+// can't be trusted for a particular CRD shape.
+type execRunner struct{}
+
+// Convert stages input and the kcl binary's output in the OS temp dir,
+// since the kcl CLI only reads and writes real files, then copies the
+// result to output.
+func (execRunner) Convert(ctx context.Context, name string, input io.Reader, output io.Writer) error {
+	inputFile, err := os.CreateTemp("", "crdtokcl-in-*.yaml")
+	if err != nil {
+		return fmt.Errorf("staging input for %s: %w", name, err)
+	}
+	defer os.Remove(inputFile.Name())
+	defer inputFile.Close()
+
+	if _, err := io.Copy(inputFile, input); err != nil {
+		return fmt.Errorf("staging input for %s: %w", name, err)
+	}
+	if err := inputFile.Close(); err != nil {
+		return fmt.Errorf("staging input for %s: %w", name, err)
+	}
+
+	outputFile, err := os.CreateTemp("", "crdtokcl-out-*.k")
+	if err != nil {
+		return fmt.Errorf("staging output for %s: %w", name, err)
+	}
+	outputFile.Close()
+	defer os.Remove(outputFile.Name())
+
+	cmd := exec.CommandContext(ctx, "kcl", "import", "-m", "crd", inputFile.Name(), "-o", outputFile.Name())
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("kcl import failed for %s: %w: %s", name, err, out)
+	}
+
+	data, err := os.ReadFile(outputFile.Name())
+	if err != nil {
+		return fmt.Errorf("reading kcl output for %s: %w", name, err)
+	}
+	_, err = output.Write(data)
+	return err
+}
+
+// fallbackRunner tries the SDK first and only shells out to the kcl
+// binary if the SDK conversion fails, so the tool keeps working in
+// environments where kcl isn't on PATH while still recovering from
+// CRDs the SDK doesn't understand yet.
+type fallbackRunner struct {
+	primary, fallback Runner
+}
+
+// NewRunner returns the Runner used by Converter.Convert and the cluster
+// ingestion pipeline by default.
+func NewRunner() Runner {
+	return fallbackRunner{primary: sdkRunner{}, fallback: execRunner{}}
+}
+
+// Convert buffers input so it can be replayed against fallback if primary
+// fails, and buffers each Runner's output so a failed attempt never
+// writes a partial result to output.
+func (r fallbackRunner) Convert(ctx context.Context, name string, input io.Reader, output io.Writer) error {
+	data, err := io.ReadAll(input)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := r.primary.Convert(ctx, name, bytes.NewReader(data), &buf); err == nil {
+		_, err := output.Write(buf.Bytes())
+		return err
+	}
+
+	buf.Reset()
+	if err := r.fallback.Convert(ctx, name, bytes.NewReader(data), &buf); err != nil {
+		return err
+	}
+	_, err = output.Write(buf.Bytes())
+	return err
+}
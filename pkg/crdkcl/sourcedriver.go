@@ -0,0 +1,370 @@
+package crdkcl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// CRDRef is a single CRD YAML file discovered by a SourceDriver.
+// URL is anything downloadFile understands: an http(s):// link or a
+// file:// path to a file already present on disk.
+type CRDRef struct {
+	Name string
+	URL  string
+}
+
+// SourceDriver lists the CRD YAML files available from a forge, a plain
+// git remote, or a local directory, without caring how the caller
+// downloads or converts them afterwards. The returned cleanup func must
+// be called once the caller is done with the CRDRefs (e.g. after
+// Download has copied any file:// refs elsewhere); it is a no-op for
+// drivers that don't stage anything on disk. ephemeral reports whether
+// the CRDRefs point into storage cleanup will remove (e.g. a temp git
+// clone), meaning they can't be reused once the caller's run ends.
+type SourceDriver interface {
+	ListCRDs(ctx context.Context) (refs []CRDRef, cleanup func(), ephemeral bool, err error)
+}
+
+// SelectDriver inspects rawURL and returns the SourceDriver responsible
+// for it: GitHub and GitLab get their respective REST APIs, anything
+// ending in ".git" or using the git:// / ssh:// schemes is cloned with
+// plain git, and an existing local directory or a file:// URL is listed
+// in place. Any other URL is an error.
+func SelectDriver(rawURL string) (SourceDriver, error) {
+	if rawURL == "" {
+		return nil, fmt.Errorf("no source URL provided")
+	}
+
+	if info, err := os.Stat(rawURL); err == nil && info.IsDir() {
+		return &LocalDriver{Dir: rawURL}, nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid source URL %q: %w", rawURL, err)
+	}
+
+	switch {
+	case strings.HasSuffix(u.Host, "github.com"):
+		return newGitHubDriverFromURL(u)
+	case strings.Contains(u.Host, "gitlab"):
+		return newGitLabDriverFromURL(u)
+	case strings.HasSuffix(u.Path, ".git") || u.Scheme == "git" || u.Scheme == "ssh":
+		return &GitDriver{RepoURL: rawURL, Ref: "HEAD"}, nil
+	case u.Scheme == "file":
+		return &LocalDriver{Dir: u.Path}, nil
+	default:
+		return nil, fmt.Errorf("could not determine a source driver for %q", rawURL)
+	}
+}
+
+// GitHubDriver lists CRDs via the GitHub Contents API
+// (GET /repos/{owner}/{repo}/contents/{path}?ref={ref}), which replaces
+// the old HTML-scraping approach and keeps working against private
+// repos when GITHUB_TOKEN is set.
+type GitHubDriver struct {
+	Owner, Repo, Path, Ref string
+	Token                  string
+	HTTPClient             *http.Client
+}
+
+// newGitHubDriverFromURL builds a GitHubDriver from a
+// https://github.com/{owner}/{repo}/tree/{ref}/{path} style URL.
+func newGitHubDriverFromURL(u *url.URL) (*GitHubDriver, error) {
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("invalid GitHub URL %q: expected /{owner}/{repo}[/tree/{ref}/{path}]", u.String())
+	}
+
+	driver := &GitHubDriver{
+		Owner: parts[0],
+		Repo:  parts[1],
+		Ref:   "HEAD",
+		Token: os.Getenv("GITHUB_TOKEN"),
+	}
+
+	if len(parts) >= 4 && parts[2] == "tree" {
+		driver.Ref = parts[3]
+		driver.Path = strings.Join(parts[4:], "/")
+	}
+
+	return driver, nil
+}
+
+// contentsEntry is the subset of the GitHub Contents API response we need.
+type contentsEntry struct {
+	Name        string `json:"name"`
+	Path        string `json:"path"`
+	Type        string `json:"type"`
+	DownloadURL string `json:"download_url"`
+}
+
+func (d *GitHubDriver) ListCRDs(ctx context.Context) ([]CRDRef, func(), bool, error) {
+	client := d.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/contents/%s", d.Owner, d.Repo, d.Path)
+	if d.Ref != "" {
+		apiURL += "?ref=" + url.QueryEscape(d.Ref)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, noopCleanup, false, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if d.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+d.Token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, noopCleanup, false, fmt.Errorf("listing %s/%s: %w", d.Owner, d.Repo, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		return nil, noopCleanup, false, fmt.Errorf("GitHub API rate limit exceeded, resets at %s", rateLimitResetTime(resp.Header))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, noopCleanup, false, fmt.Errorf("GitHub API request failed: %s", resp.Status)
+	}
+
+	var entries []contentsEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, noopCleanup, false, fmt.Errorf("decoding GitHub contents response: %w", err)
+	}
+
+	var refs []CRDRef
+	for _, entry := range entries {
+		if entry.Type != "file" || !isYAMLFile(entry.Name) {
+			continue
+		}
+		refs = append(refs, CRDRef{Name: entry.Name, URL: entry.DownloadURL})
+	}
+	return refs, noopCleanup, false, nil
+}
+
+// rateLimitResetTime formats the X-RateLimit-Reset header (seconds since
+// the epoch) for inclusion in an error message.
+func rateLimitResetTime(h http.Header) string {
+	reset, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return "unknown"
+	}
+	return time.Unix(reset, 0).Format(time.RFC3339)
+}
+
+// GitLabDriver lists CRDs via the GitLab repository tree API
+// (GET /api/v4/projects/:id/repository/tree).
+type GitLabDriver struct {
+	BaseURL, ProjectID, Path, Ref string
+	Token                         string
+	HTTPClient                    *http.Client
+}
+
+// newGitLabDriverFromURL builds a GitLabDriver from a
+// https://gitlab.example.com/{namespace}/{project}/-/tree/{ref}/{path}
+// style URL, deriving the numeric-or-path project ID from the namespace.
+func newGitLabDriverFromURL(u *url.URL) (*GitLabDriver, error) {
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	treeIdx := -1
+	for i, p := range parts {
+		if p == "-" && i+1 < len(parts) && parts[i+1] == "tree" {
+			treeIdx = i
+			break
+		}
+	}
+
+	driver := &GitLabDriver{
+		BaseURL: u.Scheme + "://" + u.Host,
+		Ref:     "HEAD",
+		Token:   os.Getenv("GITLAB_TOKEN"),
+	}
+
+	if treeIdx == -1 {
+		driver.ProjectID = strings.Join(parts, "/")
+		return driver, nil
+	}
+
+	driver.ProjectID = strings.Join(parts[:treeIdx], "/")
+	if treeIdx+3 <= len(parts) {
+		driver.Ref = parts[treeIdx+2]
+		driver.Path = strings.Join(parts[treeIdx+3:], "/")
+	}
+	return driver, nil
+}
+
+type gitlabTreeEntry struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	Type string `json:"type"`
+}
+
+func (d *GitLabDriver) ListCRDs(ctx context.Context) ([]CRDRef, func(), bool, error) {
+	client := d.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	treeURL := fmt.Sprintf("%s/api/v4/projects/%s/repository/tree", d.BaseURL, url.PathEscape(d.ProjectID))
+	q := url.Values{}
+	if d.Path != "" {
+		q.Set("path", d.Path)
+	}
+	if d.Ref != "" {
+		q.Set("ref", d.Ref)
+	}
+	q.Set("per_page", "100")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, treeURL+"?"+q.Encode(), nil)
+	if err != nil {
+		return nil, noopCleanup, false, err
+	}
+	if d.Token != "" {
+		req.Header.Set("PRIVATE-TOKEN", d.Token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, noopCleanup, false, fmt.Errorf("listing GitLab project %s: %w", d.ProjectID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, noopCleanup, false, fmt.Errorf("GitLab API request failed: %s", resp.Status)
+	}
+
+	var entries []gitlabTreeEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, noopCleanup, false, fmt.Errorf("decoding GitLab tree response: %w", err)
+	}
+
+	var refs []CRDRef
+	for _, entry := range entries {
+		if entry.Type != "blob" || !isYAMLFile(entry.Name) {
+			continue
+		}
+		rawURL := fmt.Sprintf("%s/api/v4/projects/%s/repository/files/%s/raw?ref=%s",
+			d.BaseURL, url.PathEscape(d.ProjectID), url.PathEscape(entry.Path), url.QueryEscape(d.Ref))
+		refs = append(refs, CRDRef{Name: entry.Name, URL: rawURL})
+	}
+	return refs, noopCleanup, false, nil
+}
+
+// GitDriver lists CRDs by shallow-cloning an arbitrary git remote. It
+// prefers the system `git` binary and falls back to go-git when git is
+// not on PATH.
+type GitDriver struct {
+	RepoURL, Ref, Path string
+}
+
+// ListCRDs shallow-clones RepoURL into a temp directory and lists the
+// CRDs under it. The temp directory holds the only copies of the
+// file:// refs in the returned CRDRefs, so callers must run the
+// returned cleanup func only after they're done reading those refs
+// (e.g. once Download has copied them elsewhere); ListCRDs reports
+// ephemeral as true to flag that those refs can't be relied on past
+// cleanup, unlike a CRDRef from a forge API or a persistent local dir.
+func (d *GitDriver) ListCRDs(ctx context.Context) ([]CRDRef, func(), bool, error) {
+	dir, err := os.MkdirTemp("", "crdtokcl-git-*")
+	if err != nil {
+		return nil, noopCleanup, false, fmt.Errorf("creating temp clone dir: %w", err)
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	if err := d.clone(ctx, dir); err != nil {
+		cleanup()
+		return nil, noopCleanup, false, err
+	}
+
+	refs, err := listLocalYAML(filepath.Join(dir, d.Path))
+	if err != nil {
+		cleanup()
+		return nil, noopCleanup, false, err
+	}
+	return refs, cleanup, true, nil
+}
+
+// clone shallow-clones RepoURL into dir, trying the `git` binary first
+// and falling back to the pure-Go go-git implementation.
+func (d *GitDriver) clone(ctx context.Context, dir string) error {
+	if _, err := runGitCLI(ctx, d.RepoURL, d.Ref, dir); err == nil {
+		return nil
+	}
+
+	ref := plumbing.HEAD
+	if d.Ref != "" && d.Ref != "HEAD" {
+		ref = plumbing.NewBranchReferenceName(d.Ref)
+	}
+
+	_, err := git.PlainCloneContext(ctx, dir, false, &git.CloneOptions{
+		URL:           d.RepoURL,
+		ReferenceName: ref,
+		Depth:         1,
+		SingleBranch:  true,
+	})
+	if err != nil {
+		return fmt.Errorf("cloning %s: %w", d.RepoURL, err)
+	}
+	return nil
+}
+
+// LocalDriver lists CRDs already present on disk under Dir.
+type LocalDriver struct {
+	Dir string
+}
+
+func (d *LocalDriver) ListCRDs(ctx context.Context) ([]CRDRef, func(), bool, error) {
+	refs, err := listLocalYAML(d.Dir)
+	return refs, noopCleanup, false, err
+}
+
+// isYAMLFile reports whether name looks like a YAML file.
+func isYAMLFile(name string) bool {
+	return strings.HasSuffix(name, ".yaml") || strings.HasSuffix(name, ".yml")
+}
+
+// noopCleanup is the cleanup func returned by SourceDrivers that don't
+// stage anything on disk.
+func noopCleanup() {}
+
+// DiscoverConfig selects a SourceDriver for rawURL, lists the CRDs it
+// finds, and returns the Config ready to hand to (*Converter).Run along
+// with a cleanup func. The caller must run cleanup once it's done with
+// cfg (after Download, for drivers like GitDriver whose file:// refs
+// point into a temp clone that cleanup removes). ephemeral reports
+// whether cfg.CRDs contains refs like that, which the caller must not
+// persist for later reuse (e.g. via -config) since they won't resolve
+// to anything once cleanup runs.
+func DiscoverConfig(ctx context.Context, rawURL, moduleName string) (Config, func(), bool, error) {
+	driver, err := SelectDriver(rawURL)
+	if err != nil {
+		return Config{}, noopCleanup, false, err
+	}
+
+	refs, cleanup, ephemeral, err := driver.ListCRDs(ctx)
+	if err != nil {
+		return Config{}, noopCleanup, false, fmt.Errorf("listing CRDs from %s: %w", rawURL, err)
+	}
+
+	crds := make(CRDFiles, len(refs))
+	for _, ref := range refs {
+		crds[ref.Name] = ref.URL
+	}
+
+	return Config{ModuleName: moduleName, CRDs: crds}, cleanup, ephemeral, nil
+}
@@ -0,0 +1,260 @@
+package crdkcl_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	"kclImporter/pkg/crdkcl"
+)
+
+func TestSelectDriverGitHub(t *testing.T) {
+	cases := []struct {
+		name     string
+		url      string
+		wantOwn  string
+		wantRepo string
+		wantRef  string
+		wantPath string
+	}{
+		{
+			name:     "owner and repo only",
+			url:      "https://github.com/owner/repo",
+			wantOwn:  "owner",
+			wantRepo: "repo",
+			wantRef:  "HEAD",
+			wantPath: "",
+		},
+		{
+			name:     "ref with no trailing path",
+			url:      "https://github.com/owner/repo/tree/release-1.2",
+			wantOwn:  "owner",
+			wantRepo: "repo",
+			wantRef:  "release-1.2",
+			wantPath: "",
+		},
+		{
+			name:     "ref with a subdirectory",
+			url:      "https://github.com/owner/repo/tree/release-1.2/crds",
+			wantOwn:  "owner",
+			wantRepo: "repo",
+			wantRef:  "release-1.2",
+			wantPath: "crds",
+		},
+		{
+			name:     "ref with a nested subdirectory",
+			url:      "https://github.com/owner/repo/tree/main/config/crds",
+			wantOwn:  "owner",
+			wantRepo: "repo",
+			wantRef:  "main",
+			wantPath: "config/crds",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			driver, err := crdkcl.SelectDriver(tc.url)
+			if err != nil {
+				t.Fatalf("SelectDriver(%q) = %v", tc.url, err)
+			}
+
+			gh, ok := driver.(*crdkcl.GitHubDriver)
+			if !ok {
+				t.Fatalf("SelectDriver(%q) = %T, want *crdkcl.GitHubDriver", tc.url, driver)
+			}
+			if gh.Owner != tc.wantOwn || gh.Repo != tc.wantRepo || gh.Ref != tc.wantRef || gh.Path != tc.wantPath {
+				t.Errorf("got {Owner: %q, Repo: %q, Ref: %q, Path: %q}, want {Owner: %q, Repo: %q, Ref: %q, Path: %q}",
+					gh.Owner, gh.Repo, gh.Ref, gh.Path, tc.wantOwn, tc.wantRepo, tc.wantRef, tc.wantPath)
+			}
+		})
+	}
+}
+
+func TestSelectDriverGitLab(t *testing.T) {
+	cases := []struct {
+		name     string
+		url      string
+		wantProj string
+		wantRef  string
+		wantPath string
+	}{
+		{
+			name:     "project root, no ref",
+			url:      "https://gitlab.com/group/project",
+			wantProj: "group/project",
+			wantRef:  "HEAD",
+			wantPath: "",
+		},
+		{
+			name:     "ref with no trailing path",
+			url:      "https://gitlab.com/group/project/-/tree/release-1.2",
+			wantProj: "group/project",
+			wantRef:  "release-1.2",
+			wantPath: "",
+		},
+		{
+			name:     "ref with a subdirectory",
+			url:      "https://gitlab.com/group/project/-/tree/release-1.2/crds",
+			wantProj: "group/project",
+			wantRef:  "release-1.2",
+			wantPath: "crds",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			driver, err := crdkcl.SelectDriver(tc.url)
+			if err != nil {
+				t.Fatalf("SelectDriver(%q) = %v", tc.url, err)
+			}
+
+			gl, ok := driver.(*crdkcl.GitLabDriver)
+			if !ok {
+				t.Fatalf("SelectDriver(%q) = %T, want *crdkcl.GitLabDriver", tc.url, driver)
+			}
+			if gl.ProjectID != tc.wantProj || gl.Ref != tc.wantRef || gl.Path != tc.wantPath {
+				t.Errorf("got {ProjectID: %q, Ref: %q, Path: %q}, want {ProjectID: %q, Ref: %q, Path: %q}",
+					gl.ProjectID, gl.Ref, gl.Path, tc.wantProj, tc.wantRef, tc.wantPath)
+			}
+		})
+	}
+}
+
+func TestSelectDriverGitRemoteAndLocalDir(t *testing.T) {
+	driver, err := crdkcl.SelectDriver("https://example.com/owner/repo.git")
+	if err != nil {
+		t.Fatalf("SelectDriver(.git) = %v", err)
+	}
+	if _, ok := driver.(*crdkcl.GitDriver); !ok {
+		t.Errorf("SelectDriver(.git) = %T, want *crdkcl.GitDriver", driver)
+	}
+
+	dir := t.TempDir()
+	driver, err = crdkcl.SelectDriver(dir)
+	if err != nil {
+		t.Fatalf("SelectDriver(%q) = %v", dir, err)
+	}
+	if _, ok := driver.(*crdkcl.LocalDriver); !ok {
+		t.Errorf("SelectDriver(%q) = %T, want *crdkcl.LocalDriver", dir, driver)
+	}
+}
+
+// rewriteHostTransport redirects every request to target's host, keeping
+// the original request's path and query, so a driver hardcoded to talk to
+// a real forge's API can be pointed at an httptest.Server instead.
+type rewriteHostTransport struct {
+	target *url.URL
+}
+
+func (t rewriteHostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	req.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestGitHubDriverListCRDs(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo/contents/crds", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("ref"); got != "main" {
+			t.Errorf("ref = %q, want %q", got, "main")
+		}
+		w.Write([]byte(`[
+			{"name": "widget.yaml", "type": "file", "download_url": "https://example.com/widget.yaml"},
+			{"name": "README.md", "type": "file", "download_url": "https://example.com/README.md"},
+			{"name": "sub", "type": "dir", "download_url": null}
+		]`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	target, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("parsing server URL: %v", err)
+	}
+
+	driver := &crdkcl.GitHubDriver{
+		Owner:      "owner",
+		Repo:       "repo",
+		Path:       "crds",
+		Ref:        "main",
+		HTTPClient: &http.Client{Transport: rewriteHostTransport{target: target}},
+	}
+
+	refs, cleanup, ephemeral, err := driver.ListCRDs(context.Background())
+	defer cleanup()
+	if err != nil {
+		t.Fatalf("ListCRDs() = %v", err)
+	}
+	if ephemeral {
+		t.Error("ephemeral = true, want false for a GitHub-hosted ref")
+	}
+	if len(refs) != 1 || refs[0].Name != "widget.yaml" {
+		t.Errorf("refs = %+v, want a single widget.yaml entry", refs)
+	}
+}
+
+func TestGitLabDriverListCRDs(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/projects/group%2Fproject/repository/tree", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("ref"); got != "main" {
+			t.Errorf("ref = %q, want %q", got, "main")
+		}
+		w.Write([]byte(`[
+			{"name": "widget.yaml", "path": "crds/widget.yaml", "type": "blob"},
+			{"name": "README.md", "path": "crds/README.md", "type": "blob"},
+			{"name": "sub", "path": "crds/sub", "type": "tree"}
+		]`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	driver := &crdkcl.GitLabDriver{
+		BaseURL:    server.URL,
+		ProjectID:  "group/project",
+		Path:       "crds",
+		Ref:        "main",
+		HTTPClient: server.Client(),
+	}
+
+	refs, cleanup, ephemeral, err := driver.ListCRDs(context.Background())
+	defer cleanup()
+	if err != nil {
+		t.Fatalf("ListCRDs() = %v", err)
+	}
+	if ephemeral {
+		t.Error("ephemeral = true, want false for a GitLab-hosted ref")
+	}
+	if len(refs) != 1 || refs[0].Name != "widget.yaml" {
+		t.Errorf("refs = %+v, want a single widget.yaml entry", refs)
+	}
+}
+
+func TestLocalDriverListCRDs(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(dir+"/widget.yaml", []byte("kind: CustomResourceDefinition\n"), 0644); err != nil {
+		t.Fatalf("writing fixture: %v", err)
+	}
+	if err := os.WriteFile(dir+"/README.md", []byte("not a CRD\n"), 0644); err != nil {
+		t.Fatalf("writing fixture: %v", err)
+	}
+
+	driver := &crdkcl.LocalDriver{Dir: dir}
+	refs, cleanup, ephemeral, err := driver.ListCRDs(context.Background())
+	defer cleanup()
+	if err != nil {
+		t.Fatalf("ListCRDs() = %v", err)
+	}
+	if ephemeral {
+		t.Error("ephemeral = true, want false for a persistent local directory")
+	}
+	if len(refs) != 1 || refs[0].Name != "widget.yaml" {
+		t.Errorf("refs = %+v, want a single widget.yaml entry", refs)
+	}
+}